@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+
+	"k8s.io/kubernetes/pkg/importorder"
+)
+
+// applyFix rewrites f.Path in place with its import block replaced by
+// f.FixText. It's a no-op if f carries no fix (e.g. a single,
+// unparenthesized import we don't know how to rewrite).
+func applyFix(f *importorder.Finding) error {
+	if !f.FixPos.IsValid() {
+		return nil
+	}
+
+	src, err := os.ReadFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+
+	var out []byte
+	out = append(out, src[:f.FixOffset]...)
+	out = append(out, f.FixText...)
+	out = append(out, src[f.FixEndOffset:]...)
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return fmt.Errorf("formatting rewritten imports: %w", err)
+	}
+
+	// Re-stat immediately before writing, rather than trusting any
+	// os.FileInfo collected earlier, so we reapply the file's actual
+	// current mode bits. See golang/go#38225, which goimports hit on
+	// Windows when a file's mode changed between read and write.
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return fmt.Errorf("statting file: %w", err)
+	}
+	return os.WriteFile(f.Path, formatted, info.Mode().Perm())
+}
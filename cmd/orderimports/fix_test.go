@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/importorder"
+)
+
+// findingFor builds a *importorder.Finding whose Fix* fields bound the
+// parenthesized import block in src, the same way importorder.Check
+// would, so tests can exercise applyFix without going through Check.
+func findingFor(path, src, fixText string) *importorder.Finding {
+	start := strings.Index(src, "(") + 1
+	end := strings.LastIndex(src, ")")
+	return &importorder.Finding{
+		Path:         path,
+		FixPos:       token.Pos(1), // only IsValid() is checked; any non-zero Pos does
+		FixOffset:    start,
+		FixEndOffset: end,
+		FixText:      fixText,
+	}
+}
+
+func TestApplyFixRewritesImportBlock(t *testing.T) {
+	src := "package foo\n\nimport (\n\t\"github.com/example/foo\"\n\t\"fmt\"\n)\n"
+	path := filepath.Join(t.TempDir(), "foo.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("writing source: %v", err)
+	}
+
+	f := findingFor(path, src, "\n\t\"fmt\"\n\n\t\"github.com/example/foo\"\n")
+	if err := applyFix(f); err != nil {
+		t.Fatalf("applyFix: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading fixed file: %v", err)
+	}
+	want := "package foo\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/example/foo\"\n)\n"
+	if string(got) != want {
+		t.Errorf("applyFix wrote:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestApplyFixPreservesFileMode guards the golang/go#38225 fix: applyFix
+// must reapply the file's own mode bits, not whatever os.WriteFile's
+// default would be.
+func TestApplyFixPreservesFileMode(t *testing.T) {
+	src := "package foo\n\nimport (\n\t\"github.com/example/foo\"\n\t\"fmt\"\n)\n"
+	path := filepath.Join(t.TempDir(), "foo.go")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatalf("writing source: %v", err)
+	}
+
+	f := findingFor(path, src, "\n\t\"fmt\"\n\n\t\"github.com/example/foo\"\n")
+	if err := applyFix(f); err != nil {
+		t.Fatalf("applyFix: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("file mode = %v, want 0o600", perm)
+	}
+}
+
+// TestApplyFixNoOpWithoutFixPos verifies applyFix leaves a file untouched
+// when the Finding carries no fix, e.g. a single unparenthesized import.
+func TestApplyFixNoOpWithoutFixPos(t *testing.T) {
+	src := "package foo\n\nimport \"fmt\"\n"
+	path := filepath.Join(t.TempDir(), "foo.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("writing source: %v", err)
+	}
+
+	if err := applyFix(&importorder.Finding{Path: path}); err != nil {
+		t.Fatalf("applyFix: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading file: %v", err)
+	}
+	if string(got) != src {
+		t.Errorf("applyFix modified a Finding with no FixPos; got %q, want unchanged %q", got, src)
+	}
+}
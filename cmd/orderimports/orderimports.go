@@ -16,34 +16,58 @@ limitations under the License.
 
 // verify that all the imports have our preferred order.
 // https://github.com/kubernetes/kubeadm/issues/2515
-
+//
+// The grouping logic lives in k8s.io/kubernetes/pkg/importorder, which
+// also exports a go/analysis.Analyzer for use from go vet or
+// golangci-lint. This binary is deliberately not a thin
+// singlechecker.Main(importorder.Analyzer) wrapper: it owns a concurrent
+// directory walk across the whole tree (singlechecker only drives the
+// packages given on its command line through go/packages, serially), the
+// -w rewrite and -l list modes, and -format-selectable reporting, none of
+// which the analysis.Pass API gives a driver. It reuses the same
+// importorder.Check and GroupConfig the Analyzer calls, so the two stay in
+// lockstep; skipping files by build constraint (importorder.BuildContext,
+// via -goos/-goarch/-tags) is done only here, not by the Analyzer.
+// Everything the Analyzer's own flags expose
+// (-groups, -groups-config) is mirrored here too.
 package main
 
 import (
 	"flag"
 	"fmt"
-	"go/ast"
 	"go/parser"
 	"go/token"
 	"log"
 	"os"
-	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
-	"strings"
+	"sync"
 
-	"github.com/google/go-cmp/cmp"
+	"k8s.io/kubernetes/pkg/importorder"
 )
 
 var (
 	includePath = flag.String("include-path", "", "only files with paths matching this regex are touched")
 	ignoreFile  = flag.String("ignore-file", "zz_generated", "files matching this regex are ignored")
+	groupsFlag  = flag.String("groups", "", "comma-separated, ordered list of import groups (e.g. \"stdlib,external,k8s.io,k8s.io/kubernetes\"); overridden by -groups-config")
+	groupsFile  = flag.String("groups-config", "", "path to a YAML or JSON file describing import groups; takes precedence over -groups")
+	formatFlag  = flag.String("format", "text", "output format: text, diff, json, or sarif")
+	fixFlag     = flag.Bool("w", false, "write result to (rewrite) source files instead of reporting, in the same way goimports does")
+	listFlag    = flag.Bool("l", false, "list only the paths that would be changed, mirroring gofmt conventions; combine with -w to list and rewrite")
+	goos        = flag.String("goos", "", "if set (with -goarch/-tags), skip files whose build constraint evaluates to false for this GOOS instead of the host's")
+	goarch      = flag.String("goarch", "", "if set (with -goos/-tags), skip files whose build constraint evaluates to false for this GOARCH instead of the host's")
+	tagsFlag    = flag.String("tags", "", "comma-separated build tags to treat as set when evaluating -goos/-goarch build constraints")
 )
 
+// analyzer collects Findings for a single worker; each worker owns its
+// own *token.FileSet so workers can run collect concurrently.
 type analyzer struct {
-	fset       *token.FileSet // positions are relative to fset
-	failed     bool
+	fset       *token.FileSet
 	ignoreFile *regexp.Regexp
+	groups     *importorder.GroupConfig
+	build      *importorder.BuildContext
+	findings   []*importorder.Finding
 }
 
 func newAnalyzer() *analyzer {
@@ -51,134 +75,50 @@ func newAnalyzer() *analyzer {
 	if err != nil {
 		log.Fatalf("Error compiling ignore regex: %v", err)
 	}
-
-	a := &analyzer{
+	groups, err := importorder.ResolveGroupConfig(*groupsFlag, *groupsFile)
+	if err != nil {
+		log.Fatalf("Error loading import groups: %v", err)
+	}
+	var build *importorder.BuildContext
+	if *goos != "" || *goarch != "" || *tagsFlag != "" {
+		// Only filter by build constraint when the caller actually asked
+		// for a specific GOOS/GOARCH/tags; otherwise keep checking every
+		// file, as before these flags existed.
+		build = importorder.NewBuildContext(*goos, *goarch, *tagsFlag)
+	}
+	return &analyzer{
 		fset:       token.NewFileSet(),
 		ignoreFile: ignoreFileRegexp,
+		groups:     groups,
+		build:      build,
 	}
-
-	return a
 }
 
-// collect extracts test metadata from a file.
 func (a *analyzer) collect(dir string) {
-	// create the AST by parsing src.
 	fs, err := parser.ParseDir(a.fset, dir, nil, parser.AllErrors|parser.ParseComments)
-
 	if err != nil {
 		fmt.Println(err)
-		a.failed = true
 		return
 	}
 
 	for _, p := range fs {
-		files := a.filterFiles(p.Files)
-		for _, file := range files {
-			pathToFile := a.fset.File(file.Pos()).Name()
-
-			if len(file.Imports) <= 1 {
+		for fileName, file := range p.Files {
+			if a.ignoreFile.MatchString(fileName) {
 				continue
 			}
-			var originalImports, stdlibImports, localImports, k8sImports, externalImports []string
-
-			for i, imp := range file.Imports {
-				importPath := strings.Replace(imp.Path.Value, "\"", "", -1)
-				parts := strings.Split(importPath, "/")
-
-				// if the original imports have blank line, need to add a blank line for originalImports too
-				if i != 0 && a.lineAt(imp.Pos()) > 1+a.lineAt(file.Imports[i-1].End()) {
-					originalImports = append(originalImports, "")
-				}
-				originalImports = append(originalImports, importPath)
-
-				if !strings.Contains(parts[0], ".") {
-					// standard library
-					stdlibImports = append(stdlibImports, importPath)
-				} else if strings.HasPrefix(importPath, "k8s.io/kubernetes") {
-					// local imports
-					localImports = append(localImports, importPath)
-				} else if strings.Contains(parts[0], "k8s.io") {
-					// other *.k8s.io imports
-					k8sImports = append(k8sImports, importPath)
-				} else {
-					// external repositories
-					externalImports = append(externalImports, importPath)
-				}
-			}
-
-			orderImports := []string{}
-			for _, imps := range [][]string{
-				stdlibImports,
-				externalImports,
-				k8sImports,
-				localImports,
-			} {
-				sort.Strings(imps)
-				orderImports = append(orderImports, imps...)
-				if len(imps) > 0 {
-					orderImports = append(orderImports, "")
-				}
-			}
-			// remove the last empty line, if any
-			if orderImports[len(orderImports)-1] == "" {
-				orderImports = orderImports[:len(orderImports)-1]
+			if a.build != nil && !a.build.Satisfied(a.fset, file) {
+				continue
 			}
-
-			if diff := cmp.Diff(strings.Join(originalImports, "\n"), strings.Join(orderImports, "\n")); diff != "" {
-				a.failed = true
-				fmt.Printf("%s (-got +want):\n%s", pathToFile, diff)
+			if finding := importorder.Check(a.fset, a.groups, file); finding != nil {
+				a.findings = append(a.findings, finding)
 			}
 		}
 	}
 }
 
-func (a *analyzer) lineAt(pos token.Pos) int {
-	return a.fset.PositionFor(pos, false).Line
-}
-
-func (a *analyzer) filterFiles(fs map[string]*ast.File) []*ast.File {
-	var files []*ast.File
-	for fileName, f := range fs {
-		if a.ignoreFile.MatchString(fileName) {
-			continue
-		}
-		files = append(files, f)
-	}
-	return files
-}
-
-type collector struct {
-	dirs        []string
-	includePath *regexp.Regexp
-}
-
-// handlePath walks the filesystem recursively, collecting directories,
-// ignoring some unneeded directories (hidden,vendor).
-func (c *collector) handlePath(path string, info os.FileInfo, err error) error {
-	if err != nil {
-		return err
-	}
-	if info.IsDir() {
-		// Ignore hidden directories (.git, .cache, etc)
-		if len(path) > 1 && path[0] == '.' ||
-			// OS-specific vendor code tends to be imported by OS-specific
-			// packages. We recursively typecheck imported vendored packages for
-			// each OS, but don't typecheck everything for every OS.
-			path == "vendor" ||
-			path == "_output" {
-			return filepath.SkipDir
-		}
-		if c.includePath.MatchString(path) {
-			c.dirs = append(c.dirs, path)
-		}
-	}
-	return nil
-}
-
 func main() {
 	flag.Parse()
 	args := flag.Args()
-
 	if len(args) == 0 {
 		args = append(args, ".")
 	}
@@ -187,21 +127,117 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error compiling import path regex: %v", err)
 	}
-	c := collector{includePath: includePathRegexp}
+
+	concurrency := runtime.GOMAXPROCS(0)
+
+	var dirs []string
+	seen := map[string]bool{}
 	for _, arg := range args {
-		err := filepath.Walk(arg, c.handlePath)
-		if err != nil {
+		found, errs := newWalker(includePathRegexp, concurrency).Walk(arg)
+		for _, err := range errs {
 			log.Fatalf("Error walking: %v", err)
 		}
+		for _, dir := range found {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	sort.Strings(dirs)
+
+	if !*fixFlag {
+		// goes to stderr, not stdout, so machine-readable -format output
+		// (json, sarif) stays parseable.
+		fmt.Fprintln(os.Stderr, "checking-imports-order: ")
+	}
+
+	findings := collectAll(dirs, concurrency)
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Path < findings[j].Path })
+
+	if len(findings) == 0 {
+		return
+	}
+
+	if *listFlag {
+		for _, f := range findings {
+			fmt.Println(f.Path)
+		}
+		if !*fixFlag {
+			os.Exit(1)
+		}
 	}
-	sort.Strings(c.dirs)
 
-	fmt.Println("checking-imports-order: ")
-	a := newAnalyzer()
-	for _, dir := range c.dirs {
-		a.collect(dir)
+	if *fixFlag {
+		failed := false
+		for _, f := range findings {
+			if err := applyFix(f); err != nil {
+				fmt.Printf("%s: %v\n", f.Path, err)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
 	}
-	if a.failed {
-		os.Exit(1)
+
+	rep, err := newReporter(*formatFlag, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range findings {
+		if err := rep.Report(f); err != nil {
+			log.Fatalf("Error reporting %s: %v", f.Path, err)
+		}
+	}
+	if err := rep.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	os.Exit(1)
+}
+
+// collectAll fans dirs out across a bounded pool of workers, each with its
+// own *analyzer (and so its own *token.FileSet), and gathers their
+// findings.
+func collectAll(dirs []string, concurrency int) []*importorder.Finding {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(dirs) && len(dirs) > 0 {
+		concurrency = len(dirs)
+	}
+
+	dirCh := make(chan string)
+	go func() {
+		defer close(dirCh)
+		for _, dir := range dirs {
+			dirCh <- dir
+		}
+	}()
+
+	resultCh := make(chan *analyzer, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := newAnalyzer()
+			for dir := range dirCh {
+				worker.collect(dir)
+			}
+			resultCh <- worker
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	var findings []*importorder.Finding
+	for worker := range resultCh {
+		findings = append(findings, worker.findings...)
 	}
+	return findings
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+
+	"k8s.io/kubernetes/pkg/importorder"
+)
+
+// reporter turns Findings into one of the supported output formats.
+// collect does not print violations itself; every violation flows
+// through a reporter so the CLI's -format flag is the only thing that
+// decides how results are presented.
+type reporter interface {
+	Report(f *importorder.Finding) error
+	Flush() error
+}
+
+func newReporter(format string, w io.Writer) (reporter, error) {
+	switch format {
+	case "", "text":
+		return &textReporter{w: w}, nil
+	case "diff":
+		return &diffReporter{w: w}, nil
+	case "json":
+		return &jsonReporter{w: w}, nil
+	case "sarif":
+		return &sarifReporter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q (want text, diff, json, or sarif)", format)
+	}
+}
+
+// textReporter reproduces the historical go-cmp diff output.
+type textReporter struct{ w io.Writer }
+
+func (r *textReporter) Report(f *importorder.Finding) error {
+	var err error
+	if f.Constraint != "" {
+		_, err = fmt.Fprintf(r.w, "%s [%s] (-got +want):\n%s", f.Path, f.Constraint, f.Diff)
+	} else {
+		_, err = fmt.Fprintf(r.w, "%s (-got +want):\n%s", f.Path, f.Diff)
+	}
+	return err
+}
+
+func (r *textReporter) Flush() error { return nil }
+
+// diffReporter emits a unified diff of the import block's entries, one
+// line per import as in Finding.Current and Finding.Desired. The headers
+// name the file and carry the block's first and last line; hunk line
+// numbers count from the block's first import, not from the top of the
+// file, so the output is not a patch that applies to the file as is.
+type diffReporter struct{ w io.Writer }
+
+func (r *diffReporter) Report(f *importorder.Finding) error {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.Join(f.Current, "\n") + "\n"),
+		B:        difflib.SplitLines(strings.Join(f.Desired, "\n") + "\n"),
+		FromFile: f.Path,
+		ToFile:   f.Path,
+		FromDate: fmt.Sprintf("line %d", f.StartLine),
+		ToDate:   fmt.Sprintf("line %d", f.EndLine),
+		Context:  3,
+	}
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(r.w, text)
+	return err
+}
+
+func (r *diffReporter) Flush() error { return nil }
+
+// jsonReporter emits one record per violation: file, line range, current
+// and desired ordering, and the misplaced imports within it.
+type jsonReporter struct {
+	w        io.Writer
+	findings []jsonFinding
+}
+
+type jsonFinding struct {
+	File      string                     `json:"file"`
+	StartLine int                        `json:"startLine"`
+	EndLine   int                        `json:"endLine"`
+	Current   []string                   `json:"currentOrder"`
+	Desired   []string                   `json:"desiredOrder"`
+	Misplaced []importorder.Misplacement `json:"misplaced"`
+}
+
+func (r *jsonReporter) Report(f *importorder.Finding) error {
+	r.findings = append(r.findings, jsonFinding{
+		File:      f.Path,
+		StartLine: f.StartLine,
+		EndLine:   f.EndLine,
+		Current:   f.Current,
+		Desired:   f.Desired,
+		Misplaced: f.Misplaced,
+	})
+	return nil
+}
+
+func (r *jsonReporter) Flush() error {
+	enc := json.NewEncoder(r.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.findings)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/importorder"
+)
+
+func sampleFinding() *importorder.Finding {
+	return &importorder.Finding{
+		Path:      "pkg/foo/foo.go",
+		StartLine: 3,
+		EndLine:   6,
+		Diff:      "-\t\"fmt\"\n+\t\"github.com/example/foo\"\n",
+		Current:   []string{`"github.com/example/foo"`, `"fmt"`},
+		Desired:   []string{`"fmt"`, "", `"github.com/example/foo"`},
+		Misplaced: []importorder.Misplacement{
+			{Import: "github.com/example/foo", Group: "external"},
+			{Import: "fmt", Group: "stdlib"},
+		},
+	}
+}
+
+func TestNewReporterUnknownFormat(t *testing.T) {
+	if _, err := newReporter("yaml", &bytes.Buffer{}); err == nil {
+		t.Fatal("newReporter(\"yaml\", ...) returned nil error, want one naming the supported formats")
+	}
+}
+
+func TestTextReporter(t *testing.T) {
+	var buf bytes.Buffer
+	rep, err := newReporter("text", &buf)
+	if err != nil {
+		t.Fatalf("newReporter: %v", err)
+	}
+	f := sampleFinding()
+	f.Constraint = "linux && amd64"
+	if err := rep.Report(f); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if err := rep.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	want := "pkg/foo/foo.go [linux && amd64] (-got +want):\n" + f.Diff
+	if buf.String() != want {
+		t.Errorf("text report = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDiffReporter(t *testing.T) {
+	var buf bytes.Buffer
+	rep, err := newReporter("diff", &buf)
+	if err != nil {
+		t.Fatalf("newReporter: %v", err)
+	}
+	if err := rep.Report(sampleFinding()); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if err := rep.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"--- pkg/foo/foo.go",
+		"+++ pkg/foo/foo.go",
+		`-"fmt"`,
+		`+"fmt"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("diff report = %q, want it to contain %q", out, want)
+		}
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	rep, err := newReporter("json", &buf)
+	if err != nil {
+		t.Fatalf("newReporter: %v", err)
+	}
+	f := sampleFinding()
+	if err := rep.Report(f); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if err := rep.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var got []jsonFinding
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshaling json report: %v\noutput: %s", err, buf.String())
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d findings, want 1", len(got))
+	}
+	record := got[0]
+	if record.File != f.Path || record.StartLine != f.StartLine || record.EndLine != f.EndLine {
+		t.Errorf("record = %+v, want file/line fields to match %+v", record, f)
+	}
+	if len(record.Misplaced) != len(f.Misplaced) {
+		t.Errorf("record.Misplaced = %+v, want %+v", record.Misplaced, f.Misplaced)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"k8s.io/kubernetes/pkg/importorder"
+)
+
+// sarifReporter emits SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/),
+// with each violation as a result and, where we have one, a fix carrying
+// the replacement import block text. This is what unlocks GitHub
+// code-scanning integration and inline PR comments from Prow jobs.
+type sarifReporter struct {
+	w       io.Writer
+	results []sarifResult
+}
+
+func (r *sarifReporter) Report(f *importorder.Finding) error {
+	message := fmt.Sprintf("imports are not grouped in the expected order (-got +want):\n%s", f.Diff)
+	if f.Constraint != "" {
+		message = fmt.Sprintf("[%s] %s", f.Constraint, message)
+	}
+
+	result := sarifResult{
+		RuleID:  "importorder",
+		Message: sarifMessage{Text: message},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: f.Path},
+			Region:           sarifRegion{StartLine: f.StartLine, EndLine: f.EndLine},
+		}}},
+	}
+	if f.FixPos.IsValid() {
+		result.Fixes = []sarifFix{{
+			Description: sarifMessage{Text: "reorder imports"},
+			ArtifactChanges: []sarifArtifactChange{{
+				ArtifactLocation: sarifArtifactLocation{URI: f.Path},
+				Replacements: []sarifReplacement{{
+					DeletedRegion:   sarifRegion{StartLine: f.StartLine, EndLine: f.EndLine},
+					InsertedContent: sarifArtifactContent{Text: f.FixText},
+				}},
+			}},
+		}}
+	}
+
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *sarifReporter) Flush() error {
+	doc := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name: "importorder",
+				Rules: []sarifRule{{
+					ID:               "importorder",
+					Name:             "ImportOrder",
+					ShortDescription: sarifMessage{Text: "imports must be grouped in the conventional Kubernetes order"},
+				}},
+			}},
+			Results: r.results,
+		}},
+	}
+	enc := json.NewEncoder(r.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion          `json:"deletedRegion"`
+	InsertedContent sarifArtifactContent `json:"insertedContent"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"testing"
+)
+
+func TestSarifReporterShape(t *testing.T) {
+	var buf bytes.Buffer
+	rep, err := newReporter("sarif", &buf)
+	if err != nil {
+		t.Fatalf("newReporter: %v", err)
+	}
+
+	withFix := sampleFinding()
+	withFix.Constraint = "linux"
+	withFix.FixPos = token.Pos(1)
+	withFix.FixEnd = token.Pos(2)
+	withFix.FixText = "\n\t\"fmt\"\n\n\t\"github.com/example/foo\"\n"
+	if err := rep.Report(withFix); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	noFix := sampleFinding()
+	noFix.Path = "pkg/bar/bar.go"
+	if err := rep.Report(noFix); err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+
+	if err := rep.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshaling sarif report: %v\noutput: %s", err, buf.String())
+	}
+
+	if doc.Version != "2.1.0" {
+		t.Errorf("Version = %q, want %q", doc.Version, "2.1.0")
+	}
+	if len(doc.Runs) != 1 {
+		t.Fatalf("len(Runs) = %d, want 1", len(doc.Runs))
+	}
+	run := doc.Runs[0]
+	if run.Tool.Driver.Name != "importorder" {
+		t.Errorf("Tool.Driver.Name = %q, want %q", run.Tool.Driver.Name, "importorder")
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("len(Results) = %d, want 2", len(run.Results))
+	}
+
+	got := run.Results[0]
+	if got.RuleID != "importorder" {
+		t.Errorf("Results[0].RuleID = %q, want %q", got.RuleID, "importorder")
+	}
+	loc := got.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != withFix.Path {
+		t.Errorf("Results[0] URI = %q, want %q", loc.ArtifactLocation.URI, withFix.Path)
+	}
+	if loc.Region.StartLine != withFix.StartLine || loc.Region.EndLine != withFix.EndLine {
+		t.Errorf("Results[0] region = %+v, want start=%d end=%d", loc.Region, withFix.StartLine, withFix.EndLine)
+	}
+	if len(got.Fixes) != 1 {
+		t.Fatalf("Results[0].Fixes has %d entries, want 1 (FixPos was valid)", len(got.Fixes))
+	}
+	if text := got.Fixes[0].ArtifactChanges[0].Replacements[0].InsertedContent.Text; text != withFix.FixText {
+		t.Errorf("Fixes[0] InsertedContent = %q, want %q", text, withFix.FixText)
+	}
+
+	if len(run.Results[1].Fixes) != 0 {
+		t.Errorf("Results[1].Fixes = %+v, want none (FixPos was never set)", run.Results[1].Fixes)
+	}
+}
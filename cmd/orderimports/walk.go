@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// walker discovers candidate package directories concurrently, in the
+// style of golang.org/x/tools/internal/fastwalk: directory reads
+// (os.ReadDir) fan out across the tree, with a semaphore bounding how many
+// are in flight at once so we don't exhaust file descriptors on huge
+// trees like k/k.
+type walker struct {
+	includePath *regexp.Regexp
+	sem         chan struct{}
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	dirs []string
+	errs []error
+}
+
+func newWalker(includePath *regexp.Regexp, concurrency int) *walker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &walker{includePath: includePath, sem: make(chan struct{}, concurrency)}
+}
+
+// Walk walks root and returns every directory matching w.includePath, in
+// deterministic (sorted) order, along with any errors encountered.
+func (w *walker) Walk(root string) ([]string, []error) {
+	w.wg.Add(1)
+	go w.scan(root)
+	w.wg.Wait()
+
+	sort.Strings(w.dirs)
+	return w.dirs, w.errs
+}
+
+// shouldSkipDir reports whether path should be skipped entirely: hidden
+// directories (.git, .cache, etc), OS-specific vendor code, which is
+// imported by OS-specific packages rather than typechecked directly, and
+// _output. It compares the whole path, not its last element, so when
+// walking from "." only top-level names match (a/.git and a/vendor are
+// walked).
+func shouldSkipDir(path string) bool {
+	return len(path) > 1 && path[0] == '.' ||
+		path == "vendor" ||
+		path == "_output"
+}
+
+func (w *walker) scan(path string) {
+	defer w.wg.Done()
+
+	if shouldSkipDir(path) {
+		return
+	}
+	if w.includePath.MatchString(path) {
+		w.mu.Lock()
+		w.dirs = append(w.dirs, path)
+		w.mu.Unlock()
+	}
+
+	w.sem <- struct{}{}
+	entries, err := os.ReadDir(path)
+	<-w.sem
+	if err != nil {
+		w.mu.Lock()
+		w.errs = append(w.errs, err)
+		w.mu.Unlock()
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		child := filepath.Join(path, entry.Name())
+		w.wg.Add(1)
+		go w.scan(child)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func mkdirAll(t *testing.T, root, rel string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, rel), 0o755); err != nil {
+		t.Fatalf("MkdirAll(%s): %v", rel, err)
+	}
+}
+
+// chdir switches to dir for the duration of the test and restores the
+// original working directory afterward, so relative paths returned by
+// Walk (and shouldSkipDir's exact "vendor"/"_output" match) behave the
+// way they do for a real invocation rooted at ".".
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Chdir(%s): %v", dir, err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(cwd); err != nil {
+			t.Fatalf("restoring cwd: %v", err)
+		}
+	})
+}
+
+func TestWalkerSkipsHiddenVendorAndOutputDirs(t *testing.T) {
+	root := t.TempDir()
+	mkdirAll(t, root, "a")
+	mkdirAll(t, root, filepath.Join("a", "b"))
+	mkdirAll(t, root, filepath.Join(".git", "objects"))
+	mkdirAll(t, root, filepath.Join("vendor", "pkg"))
+	mkdirAll(t, root, filepath.Join("_output", "bin"))
+	chdir(t, root)
+
+	dirs, errs := newWalker(regexp.MustCompile(""), 4).Walk(".")
+	if len(errs) != 0 {
+		t.Fatalf("Walk errs = %v", errs)
+	}
+
+	want := []string{".", "a", filepath.Join("a", "b")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("Walk(\".\") = %v, want %v", dirs, want)
+	}
+}
+
+// TestWalkerIncludePathFilters verifies that only directories matching
+// includePath are returned, even though every non-skipped directory is
+// still traversed to find matching descendants.
+func TestWalkerIncludePathFilters(t *testing.T) {
+	root := t.TempDir()
+	mkdirAll(t, root, filepath.Join("pkg", "foo"))
+	mkdirAll(t, root, filepath.Join("pkg", "bar"))
+	mkdirAll(t, root, filepath.Join("cmd", "foo"))
+	chdir(t, root)
+
+	dirs, errs := newWalker(regexp.MustCompile(`^pkg/`), 4).Walk(".")
+	if len(errs) != 0 {
+		t.Fatalf("Walk errs = %v", errs)
+	}
+
+	want := []string{filepath.Join("pkg", "bar"), filepath.Join("pkg", "foo")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("Walk(\".\") = %v, want %v", dirs, want)
+	}
+}
+
+// TestWalkerDeterministicAcrossConcurrency runs the same tree through
+// walkers with different concurrency levels and checks they all return
+// the identical sorted directory list, i.e. that fanning the os.ReadDir
+// calls out across goroutines doesn't make the result order (or
+// membership) depend on scheduling.
+func TestWalkerDeterministicAcrossConcurrency(t *testing.T) {
+	root := t.TempDir()
+	for _, rel := range []string{"a", "b", "c", filepath.Join("a", "x"), filepath.Join("a", "y"), filepath.Join("c", "z")} {
+		mkdirAll(t, root, rel)
+	}
+	chdir(t, root)
+
+	var first []string
+	for _, concurrency := range []int{1, 2, 8} {
+		dirs, errs := newWalker(regexp.MustCompile(""), concurrency).Walk(".")
+		if len(errs) != 0 {
+			t.Fatalf("concurrency=%d: Walk errs = %v", concurrency, errs)
+		}
+		if first == nil {
+			first = dirs
+			continue
+		}
+		if !reflect.DeepEqual(dirs, first) {
+			t.Errorf("concurrency=%d: Walk(\".\") = %v, want %v", concurrency, dirs, first)
+		}
+	}
+}
+
+func TestShouldSkipDir(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".", false},
+		{".git", true},
+		{".cache", true},
+		{"vendor", true},
+		{"_output", true},
+		{"pkg", false},
+		{"a/vendor", false}, // shouldSkipDir only matches exact top-level names
+	}
+	for _, tt := range tests {
+		if got := shouldSkipDir(tt.path); got != tt.want {
+			t.Errorf("shouldSkipDir(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
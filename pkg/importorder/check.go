@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Misplacement names a single import that isn't in the group it belongs
+// in, and the group it should move to.
+type Misplacement struct {
+	Import string
+	Group  string
+}
+
+// Finding describes one file whose imports aren't grouped in the
+// expected order. It carries enough structured detail (not just a diff
+// string) for machine-readable reporters (JSON, SARIF) as well as for
+// building a SuggestedFix.
+type Finding struct {
+	Path       string
+	StartLine  int
+	EndLine    int
+	Diff       string
+	Current    []string
+	Desired    []string
+	Misplaced  []Misplacement
+	Constraint string // effective build constraint, if any
+
+	// FixPos/FixEnd bound the contents of the import block (inside the
+	// parens), as token.Pos values against the *token.FileSet passed to
+	// Check; FixOffset/FixEndOffset are the same range as plain byte
+	// offsets into the file, for callers that only have the raw source
+	// and no FileSet of their own. FixText is what the range should be
+	// replaced with. FixPos is invalid (token.NoPos) if the file has no
+	// parenthesized import block to rewrite, e.g. a single unparenthesized
+	// import, or if its imports are spread over more than one import
+	// declaration.
+	FixPos, FixEnd          token.Pos
+	FixOffset, FixEndOffset int
+	FixText                 string
+}
+
+// Check analyzes a single file's import block against cfg and returns a
+// Finding if it isn't grouped correctly, or nil if it already is.
+func Check(fset *token.FileSet, cfg *GroupConfig, file *ast.File) *Finding {
+	if len(file.Imports) <= 1 {
+		return nil
+	}
+
+	groups := groupSpecs(cfg, file.Imports)
+
+	var current, desired []string
+	for i, imp := range file.Imports {
+		if i != 0 && lineFor(fset, imp.Pos()) > 1+lineFor(fset, file.Imports[i-1].End()) {
+			current = append(current, "")
+		}
+		current = append(current, specRepr(imp))
+	}
+	for _, specs := range groups {
+		for _, spec := range specs {
+			desired = append(desired, specRepr(spec))
+		}
+		if len(specs) > 0 {
+			desired = append(desired, "")
+		}
+	}
+	if len(desired) > 0 && desired[len(desired)-1] == "" {
+		desired = desired[:len(desired)-1]
+	}
+
+	diff := cmp.Diff(strings.Join(current, "\n"), strings.Join(desired, "\n"))
+	if diff == "" {
+		return nil
+	}
+
+	f := &Finding{
+		Path:      fset.PositionFor(file.Pos(), false).Filename,
+		StartLine: fset.PositionFor(file.Imports[0].Pos(), false).Line,
+		EndLine:   fset.PositionFor(file.Imports[len(file.Imports)-1].End(), false).Line,
+		Diff:      diff,
+		Current:   current,
+		Desired:   desired,
+		Misplaced: misplacedImports(cfg, file.Imports, groups),
+	}
+	if expr := fileConstraint(fset, file); expr != nil {
+		f.Constraint = expr.String()
+	}
+	if decl := importDecl(file); decl != nil && decl.Lparen.IsValid() {
+		f.FixPos, f.FixEnd = decl.Lparen+1, decl.Rparen
+		f.FixOffset = fset.PositionFor(f.FixPos, false).Offset
+		f.FixEndOffset = fset.PositionFor(f.FixEnd, false).Offset
+		f.FixText = "\n" + renderGroups(groups)
+	}
+	return f
+}
+
+// misplacedImports reports, for each original import, the group it
+// should be in whenever that differs from the group implied by its
+// current position.
+//
+// Position is determined independently of blank lines: specs is walked in
+// source order, and each maximal run of consecutive imports classified
+// into the same group counts as one "block" (whether or not a blank line
+// actually separates it from its neighbors — a missing separator is
+// already reflected in Diff/Current/Desired). An import is misplaced if
+// its block's ordinal doesn't match its group's ordinal among the
+// non-empty target groups, e.g. an unseparated external-then-stdlib pair
+// flags both imports, while an unseparated stdlib-then-external pair flags
+// neither.
+func misplacedImports(cfg *GroupConfig, specs []*ast.ImportSpec, groups [][]*ast.ImportSpec) []Misplacement {
+	wantPosition := make(map[int]int, len(groups))
+	for i, g := range groups {
+		if len(g) > 0 {
+			wantPosition[i] = len(wantPosition)
+		}
+	}
+
+	var misplaced []Misplacement
+	block, lastWant := -1, -1
+	for i, spec := range specs {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		want := cfg.classify(importPath)
+		if i == 0 || want != lastWant {
+			block++
+		}
+		lastWant = want
+		if wantPosition[want] != block {
+			misplaced = append(misplaced, Misplacement{Import: importPath, Group: cfg.defs[want].Name})
+		}
+	}
+	return misplaced
+}
+
+func lineFor(fset *token.FileSet, pos token.Pos) int {
+	return fset.PositionFor(pos, false).Line
+}
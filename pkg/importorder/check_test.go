@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import "testing"
+
+// TestCheckMisplacedWithinUnseparatedBlock verifies that every
+// wrongly-grouped import in an unseparated block is reported, not just
+// the first one whose group happens to differ from the running block
+// counter.
+func TestCheckMisplacedWithinUnseparatedBlock(t *testing.T) {
+	src := `package foo
+
+import (
+	"github.com/external/pkg"
+	"fmt"
+)
+`
+	fset, file := mustParse(t, src)
+	finding := Check(fset, defaultGroupConfig(), file)
+	if finding == nil {
+		t.Fatal("Check returned nil, want a finding for misordered imports")
+	}
+
+	got := map[string]string{}
+	for _, m := range finding.Misplaced {
+		got[m.Import] = m.Group
+	}
+	want := map[string]string{
+		"github.com/external/pkg": "external",
+		"fmt":                     "stdlib",
+	}
+	for imp, group := range want {
+		if got[imp] != group {
+			t.Errorf("Misplaced[%q] = %q, want %q (Misplaced: %+v)", imp, got[imp], group, finding.Misplaced)
+		}
+	}
+}
+
+// TestCheckMisplacedAlreadyCorrect verifies that a correctly-grouped
+// import block, even without blank-line separation between groups,
+// reports no misplaced imports (the missing separator is still visible
+// via Diff/Current/Desired).
+func TestCheckMisplacedAlreadyCorrect(t *testing.T) {
+	src := `package foo
+
+import (
+	"fmt"
+	"github.com/external/pkg"
+)
+`
+	fset, file := mustParse(t, src)
+	finding := Check(fset, defaultGroupConfig(), file)
+	if finding == nil {
+		t.Fatal("Check returned nil, want a finding for the missing blank-line separator")
+	}
+	if len(finding.Misplaced) != 0 {
+		t.Errorf("Misplaced = %+v, want none", finding.Misplaced)
+	}
+}
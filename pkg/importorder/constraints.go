@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+	"runtime"
+	"strings"
+)
+
+// fileConstraint returns the effective build constraint for file, derived
+// from any "//go:build" or "// +build" lines that appear before its
+// package clause, so diagnostics can say which OS/arch a violation
+// belongs to. It returns a nil Expr if the file carries no build
+// constraint. The framework itself (go/packages) has already selected
+// files for the active build context by the time Run sees them, so there
+// it is purely informational; BuildContext.Satisfied also uses it to
+// decide whether a file is checked at all.
+func fileConstraint(fset *token.FileSet, file *ast.File) constraint.Expr {
+	packageLine := fset.PositionFor(file.Package, false).Line
+
+	var goBuild constraint.Expr
+	var plusBuild []constraint.Expr
+	for _, cg := range file.Comments {
+		if fset.PositionFor(cg.End(), false).Line >= packageLine {
+			continue
+		}
+		for _, c := range cg.List {
+			line := c.Text
+			if constraint.IsGoBuild(line) {
+				if expr, err := constraint.Parse(line); err == nil {
+					goBuild = expr
+				}
+				continue
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), "// +build") {
+				if expr, err := constraint.Parse(line); err == nil {
+					plusBuild = append(plusBuild, expr)
+				}
+			}
+		}
+	}
+
+	if goBuild != nil {
+		return goBuild
+	}
+	if len(plusBuild) == 0 {
+		return nil
+	}
+	combined := plusBuild[0]
+	for _, expr := range plusBuild[1:] {
+		combined = &constraint.AndExpr{X: combined, Y: expr}
+	}
+	return combined
+}
+
+// unixGOOS lists the GOOS values the "unix" build-tag convention
+// (https://pkg.go.dev/go/build#hdr-Build_Constraints) expands to, so
+// BuildContext can honor "//go:build unix" the same way go/build does.
+var unixGOOS = map[string]bool{
+	"aix":       true,
+	"android":   true,
+	"darwin":    true,
+	"dragonfly": true,
+	"freebsd":   true,
+	"hurd":      true,
+	"illumos":   true,
+	"ios":       true,
+	"linux":     true,
+	"netbsd":    true,
+	"openbsd":   true,
+	"solaris":   true,
+}
+
+// BuildContext evaluates a file's build constraint against an explicit
+// GOOS, GOARCH, and set of build tags, so callers can ask "would this
+// file be compiled for linux/arm64" without depending on the host's own
+// runtime.GOOS/GOARCH.
+type BuildContext struct {
+	goos, goarch string
+	tags         map[string]bool
+}
+
+// NewBuildContext builds a BuildContext from the -goos/-goarch/-tags flag
+// values. An empty goos or goarch falls back to the host's
+// runtime.GOOS/GOARCH, matching go/build's own default. tagsList is a
+// comma-separated list of additional build tags to treat as set.
+func NewBuildContext(goos, goarch, tagsList string) *BuildContext {
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	tags := map[string]bool{}
+	for _, tag := range strings.Split(tagsList, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags[tag] = true
+		}
+	}
+	return &BuildContext{goos: goos, goarch: goarch, tags: tags}
+}
+
+// Satisfied reports whether file would be compiled under bc: true if the
+// file carries no build constraint, or its constraint evaluates to true
+// against bc's GOOS, GOARCH, and tags.
+func (bc *BuildContext) Satisfied(fset *token.FileSet, file *ast.File) bool {
+	expr := fileConstraint(fset, file)
+	if expr == nil {
+		return true
+	}
+	return expr.Eval(bc.hasTag)
+}
+
+// hasTag implements the go/build/constraint.Expr.Eval callback: besides
+// the tags an -tags flag added explicitly, it recognizes bc's own
+// goos/goarch (and the "unix" pseudo-tag derived from goos) the way
+// go/build's own constraint evaluation does.
+func (bc *BuildContext) hasTag(tag string) bool {
+	switch tag {
+	case bc.goos:
+		return true
+	case bc.goarch:
+		return true
+	case "unix":
+		return unixGOOS[bc.goos]
+	default:
+		return bc.tags[tag]
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import "testing"
+
+func TestFileConstraint(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string // "" means no constraint
+	}{
+		{
+			name: "no constraint",
+			src: `package foo
+
+import "fmt"
+`,
+			want: "",
+		},
+		{
+			name: "go:build line",
+			src: `//go:build linux && amd64
+
+package foo
+
+import "fmt"
+`,
+			want: "linux && amd64",
+		},
+		{
+			name: "plus-build lines combine with AND",
+			src: `// +build linux
+// +build amd64
+
+package foo
+
+import "fmt"
+`,
+			want: "linux && amd64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, file := mustParse(t, tt.src)
+			expr := fileConstraint(fset, file)
+			got := ""
+			if expr != nil {
+				got = expr.String()
+			}
+			if got != tt.want {
+				t.Errorf("fileConstraint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildContextSatisfied(t *testing.T) {
+	tests := []struct {
+		name          string
+		goos, goarch  string
+		tags          string
+		src           string
+		wantSatisfied bool
+	}{
+		{
+			name:          "no constraint always satisfied",
+			goos:          "windows",
+			src:           "package foo\n\nimport \"fmt\"\n",
+			wantSatisfied: true,
+		},
+		{
+			name:          "matching goos",
+			goos:          "linux",
+			src:           "//go:build linux\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: true,
+		},
+		{
+			name:          "mismatched goos",
+			goos:          "windows",
+			src:           "//go:build linux\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: false,
+		},
+		{
+			name:          "unix pseudo-tag covers linux",
+			goos:          "linux",
+			src:           "//go:build unix\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: true,
+		},
+		{
+			name:          "unix pseudo-tag excludes windows",
+			goos:          "windows",
+			src:           "//go:build unix\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: false,
+		},
+		{
+			name:          "explicit tag required",
+			goos:          "linux",
+			tags:          "e2e",
+			src:           "//go:build e2e\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: true,
+		},
+		{
+			name:          "explicit tag missing",
+			goos:          "linux",
+			src:           "//go:build e2e\n\npackage foo\n\nimport \"fmt\"\n",
+			wantSatisfied: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset, file := mustParse(t, tt.src)
+			bc := NewBuildContext(tt.goos, tt.goarch, tt.tags)
+			if got := bc.Satisfied(fset, file); got != tt.wantSatisfied {
+				t.Errorf("Satisfied() = %v, want %v", got, tt.wantSatisfied)
+			}
+		})
+	}
+}
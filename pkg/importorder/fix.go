@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// importDecl returns the file's single import declaration, or nil if the
+// file has none or more than one.
+func importDecl(file *ast.File) *ast.GenDecl {
+	var found *ast.GenDecl
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			if found != nil {
+				return nil
+			}
+			found = gen
+		}
+	}
+	return found
+}
+
+// renderGroups renders groups as the lines that should appear inside an
+// import block, with exactly one blank line between non-empty groups.
+func renderGroups(groups [][]*ast.ImportSpec) string {
+	var block strings.Builder
+	wroteGroup := false
+	for _, specs := range groups {
+		if len(specs) == 0 {
+			continue
+		}
+		if wroteGroup {
+			block.WriteString("\n")
+		}
+		wroteGroup = true
+		for _, spec := range specs {
+			for _, line := range renderSpecLines(spec) {
+				block.WriteString("\t")
+				block.WriteString(line)
+				block.WriteString("\n")
+			}
+		}
+	}
+	return block.String()
+}
+
+// renderSpecLines renders an import spec as the lines that should appear
+// inside the import block: any doc comment above it, followed by the
+// (possibly aliased) import path and its trailing comment, if any.
+func renderSpecLines(spec *ast.ImportSpec) []string {
+	var lines []string
+	if spec.Doc != nil {
+		for _, c := range spec.Doc.List {
+			lines = append(lines, c.Text)
+		}
+	}
+	lines = append(lines, specRepr(spec))
+	return lines
+}
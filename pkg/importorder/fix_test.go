@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func mustParse(t *testing.T, src string) (*token.FileSet, *ast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing source: %v", err)
+	}
+	return fset, file
+}
+
+// TestCheckFixPreservesSpecDetail verifies that the suggested fix keeps
+// everything specRepr/renderSpecLines are responsible for: aliases, blank
+// "_" and dot "." imports, and trailing comments, rather than just
+// reordering bare import paths.
+func TestCheckFixPreservesSpecDetail(t *testing.T) {
+	src := `package foo
+
+import (
+	foo "github.com/example/foo" // keep this alias
+	"fmt"
+	_ "github.com/example/blank"
+	. "github.com/example/dot"
+)
+`
+	fset, file := mustParse(t, src)
+	finding := Check(fset, defaultGroupConfig(), file)
+	if finding == nil {
+		t.Fatal("Check returned nil, want a finding for misordered imports")
+	}
+
+	want := "\n" +
+		"\t\"fmt\"\n" +
+		"\n" +
+		"\t_ \"github.com/example/blank\"\n" +
+		"\t. \"github.com/example/dot\"\n" +
+		"\tfoo \"github.com/example/foo\" // keep this alias\n"
+	if finding.FixText != want {
+		t.Errorf("FixText = %q, want %q", finding.FixText, want)
+	}
+}
+
+// TestCheckFixPreservesDocComment verifies that a doc comment attached to
+// an import spec travels with it when the fix reorders groups.
+func TestCheckFixPreservesDocComment(t *testing.T) {
+	src := `package foo
+
+import (
+	"github.com/example/foo"
+
+	// explains why we need encoding/json
+	"encoding/json"
+)
+`
+	fset, file := mustParse(t, src)
+	finding := Check(fset, defaultGroupConfig(), file)
+	if finding == nil {
+		t.Fatal("Check returned nil, want a finding for misordered imports")
+	}
+
+	want := "\n" +
+		"\t// explains why we need encoding/json\n" +
+		"\t\"encoding/json\"\n" +
+		"\n" +
+		"\t\"github.com/example/foo\"\n"
+	if finding.FixText != want {
+		t.Errorf("FixText = %q, want %q", finding.FixText, want)
+	}
+}
+
+// TestCheckNoFixForUnparenthesizedImport verifies that a single,
+// unparenthesized import (which has no Lparen to anchor a rewrite) is
+// reported with no fix rather than a bogus one.
+func TestCheckNoFixForUnparenthesizedImport(t *testing.T) {
+	src := `package foo
+
+import "fmt"
+import "github.com/example/foo"
+`
+	fset, file := mustParse(t, src)
+	finding := Check(fset, defaultGroupConfig(), file)
+	if finding == nil {
+		t.Fatal("Check returned nil, want a finding for two separate import decls")
+	}
+	if finding.FixPos.IsValid() {
+		t.Errorf("FixPos is valid for an unparenthesized import; want token.NoPos")
+	}
+}
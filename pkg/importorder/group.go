@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// groupSpecs splits specs into the groups configured in cfg, in cfg's
+// order. Each group is sorted by import path.
+func groupSpecs(cfg *GroupConfig, specs []*ast.ImportSpec) [][]*ast.ImportSpec {
+	groups := make([][]*ast.ImportSpec, len(cfg.defs))
+	for _, spec := range specs {
+		importPath := strings.Trim(spec.Path.Value, `"`)
+		idx := cfg.classify(importPath)
+		groups[idx] = append(groups[idx], spec)
+	}
+	for _, g := range groups {
+		sort.Slice(g, func(i, j int) bool { return g[i].Path.Value < g[j].Path.Value })
+	}
+	return groups
+}
+
+// specRepr renders a single import spec as one line, preserving its
+// alias (including blank "_" and dot "." imports) and any trailing
+// line comment, for use in diffing.
+func specRepr(spec *ast.ImportSpec) string {
+	var parts []string
+	if spec.Name != nil {
+		parts = append(parts, spec.Name.Name)
+	}
+	parts = append(parts, spec.Path.Value)
+	line := strings.Join(parts, " ")
+	if spec.Comment != nil && len(spec.Comment.List) > 0 {
+		line += " " + spec.Comment.List[0].Text
+	}
+	return line
+}
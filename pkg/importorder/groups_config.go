@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// stdlibGroupName is the implicit group of standard library imports
+	// (those whose first path element has no dot).
+	stdlibGroupName = "stdlib"
+	// externalGroupName is the implicit catch-all group for any import
+	// that doesn't match a more specific group.
+	externalGroupName = "external"
+)
+
+// groupDef describes one import group: a human-readable name and the set
+// of path prefixes and/or regexes that place an import path in it. The
+// stdlib and external groups are implicit and carry no prefixes/patterns.
+type groupDef struct {
+	Name     string   `json:"name" yaml:"name"`
+	Prefixes []string `json:"prefixes,omitempty" yaml:"prefixes,omitempty"`
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+
+	regexes []*regexp.Regexp
+}
+
+// GroupConfig is the compiled, ordered list of import groups used to sort
+// an import block.
+type GroupConfig struct {
+	defs []groupDef
+}
+
+// defaultGroupConfig reproduces Kubernetes' historical four-group layout:
+// standard library, external repositories, other k8s.io repositories (any
+// import whose first path element contains "k8s.io", e.g. sigs.k8s.io/yaml),
+// and k8s.io/kubernetes itself.
+func defaultGroupConfig() *GroupConfig {
+	cfg, err := newGroupConfig([]groupDef{
+		{Name: stdlibGroupName},
+		{Name: externalGroupName},
+		{Name: "k8s.io", Prefixes: []string{"k8s.io"}, Patterns: []string{`^[^/]*k8s\.io`}},
+		{Name: "k8s.io/kubernetes", Prefixes: []string{"k8s.io/kubernetes"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// parseGroupsFlag builds a GroupConfig from a comma-separated, ordered
+// list of group tokens, e.g.
+// "stdlib,external,k8s.io,k8s.io/kubernetes/staging,k8s.io/kubernetes".
+// The special tokens "stdlib" and "external" refer to the implicit
+// groups; every other token is treated as a single path prefix.
+func parseGroupsFlag(s string) (*GroupConfig, error) {
+	var defs []groupDef
+	for _, token := range strings.Split(s, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		switch token {
+		case stdlibGroupName, externalGroupName:
+			defs = append(defs, groupDef{Name: token})
+		default:
+			defs = append(defs, groupDef{Name: token, Prefixes: []string{token}})
+		}
+	}
+	return newGroupConfig(defs)
+}
+
+// loadGroupConfigFile reads an ordered list of group definitions from a
+// YAML or JSON file (selected by extension; YAML also parses plain JSON).
+func loadGroupConfigFile(path string) (*GroupConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading groups config: %w", err)
+	}
+
+	var defs []groupDef
+	unmarshal := yaml.Unmarshal
+	if filepath.Ext(path) == ".json" {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("parsing groups config %s: %w", path, err)
+	}
+	return newGroupConfig(defs)
+}
+
+// newGroupConfig compiles defs, inserting the implicit stdlib and external
+// groups if the caller didn't place them explicitly, so every config has
+// somewhere to put every import.
+func newGroupConfig(defs []groupDef) (*GroupConfig, error) {
+	for i := range defs {
+		for _, pattern := range defs[i].Patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("compiling pattern %q for group %q: %w", pattern, defs[i].Name, err)
+			}
+			defs[i].regexes = append(defs[i].regexes, re)
+		}
+	}
+
+	hasStdlib, hasExternal := false, false
+	for _, d := range defs {
+		switch d.Name {
+		case stdlibGroupName:
+			hasStdlib = true
+		case externalGroupName:
+			hasExternal = true
+		}
+	}
+	if !hasStdlib {
+		defs = append([]groupDef{{Name: stdlibGroupName}}, defs...)
+	}
+	if !hasExternal {
+		defs = append(defs, groupDef{Name: externalGroupName})
+	}
+
+	return &GroupConfig{defs: defs}, nil
+}
+
+// classify returns the index into cfg.defs that importPath belongs in. A
+// path matching more than one prefix/pattern group goes to the most
+// specific (longest) match, so that e.g. "k8s.io/kubernetes" isn't
+// misclassified under a plain "k8s.io" group.
+func (cfg *GroupConfig) classify(importPath string) int {
+	firstElem := importPath
+	if i := strings.Index(importPath, "/"); i >= 0 {
+		firstElem = importPath[:i]
+	}
+	if !strings.Contains(firstElem, ".") {
+		for i, d := range cfg.defs {
+			if d.Name == stdlibGroupName {
+				return i
+			}
+		}
+	}
+
+	best, bestLen := -1, -1
+	for i, d := range cfg.defs {
+		for _, prefix := range d.Prefixes {
+			if matchesPrefix(importPath, prefix) && len(prefix) > bestLen {
+				best, bestLen = i, len(prefix)
+			}
+		}
+		for _, re := range d.regexes {
+			if loc := re.FindStringIndex(importPath); loc != nil && loc[1]-loc[0] > bestLen {
+				best, bestLen = i, loc[1]-loc[0]
+			}
+		}
+	}
+	if best >= 0 {
+		return best
+	}
+
+	for i, d := range cfg.defs {
+		if d.Name == externalGroupName {
+			return i
+		}
+	}
+	// unreachable: newGroupConfig always ensures an external group exists.
+	return len(cfg.defs) - 1
+}
+
+// matchesPrefix reports whether importPath is prefix, or a subpackage of
+// prefix (i.e. prefix followed by "/" or end of string).
+func matchesPrefix(importPath, prefix string) bool {
+	if !strings.HasPrefix(importPath, prefix) {
+		return false
+	}
+	return len(importPath) == len(prefix) || importPath[len(prefix)] == '/'
+}
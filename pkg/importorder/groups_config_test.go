@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importorder
+
+import "testing"
+
+func TestDefaultGroupConfigClassify(t *testing.T) {
+	cfg := defaultGroupConfig()
+
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"fmt", stdlibGroupName},
+		{"encoding/json", stdlibGroupName},
+		{"github.com/google/go-cmp/cmp", externalGroupName},
+		{"k8s.io/apimachinery/pkg/util/sets", "k8s.io"},
+		// "k8s.io/kubernetes" is itself a prefix match for the plain
+		// "k8s.io" group too; the longest (most specific) prefix must win.
+		{"k8s.io/kubernetes/pkg/apis/core", "k8s.io/kubernetes"},
+		{"k8s.io/kubernetesui/dashboard", "k8s.io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			idx := cfg.classify(tt.importPath)
+			if got := cfg.defs[idx].Name; got != tt.want {
+				t.Errorf("classify(%q) = %q, want %q", tt.importPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroupsFlag(t *testing.T) {
+	cfg, err := parseGroupsFlag("stdlib, external, k8s.io, k8s.io/kubernetes/staging, k8s.io/kubernetes")
+	if err != nil {
+		t.Fatalf("parseGroupsFlag: %v", err)
+	}
+
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{"fmt", stdlibGroupName},
+		{"github.com/google/go-cmp/cmp", externalGroupName},
+		{"k8s.io/apimachinery/pkg/util/sets", "k8s.io"},
+		{"k8s.io/kubernetes/staging/src/k8s.io/api", "k8s.io/kubernetes/staging"},
+		{"k8s.io/kubernetes/pkg/apis/core", "k8s.io/kubernetes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			idx := cfg.classify(tt.importPath)
+			if got := cfg.defs[idx].Name; got != tt.want {
+				t.Errorf("classify(%q) = %q, want %q", tt.importPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGroupsFlagInsertsImplicitGroups(t *testing.T) {
+	// Neither "stdlib" nor "external" is named explicitly; newGroupConfig
+	// must still insert them so every import path has somewhere to go.
+	cfg, err := parseGroupsFlag("k8s.io")
+	if err != nil {
+		t.Fatalf("parseGroupsFlag: %v", err)
+	}
+
+	if idx := cfg.classify("fmt"); cfg.defs[idx].Name != stdlibGroupName {
+		t.Errorf("classify(%q) = %q, want %q", "fmt", cfg.defs[idx].Name, stdlibGroupName)
+	}
+	if idx := cfg.classify("github.com/google/go-cmp/cmp"); cfg.defs[idx].Name != externalGroupName {
+		t.Errorf("classify(%q) = %q, want %q", "github.com/google/go-cmp/cmp", cfg.defs[idx].Name, externalGroupName)
+	}
+}
+
+func TestGroupConfigClassifyPatternRegex(t *testing.T) {
+	cfg, err := newGroupConfig([]groupDef{
+		{Name: "generated", Patterns: []string{`/generated/`}},
+	})
+	if err != nil {
+		t.Fatalf("newGroupConfig: %v", err)
+	}
+
+	idx := cfg.classify("k8s.io/kubernetes/pkg/client/generated/clientset")
+	if got := cfg.defs[idx].Name; got != "generated" {
+		t.Errorf("classify() = %q, want %q", got, "generated")
+	}
+}
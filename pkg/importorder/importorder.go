@@ -0,0 +1,103 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importorder checks that an import block is grouped into
+// Kubernetes' conventional order: standard library, external
+// repositories, other k8s.io/* repositories, and k8s.io/kubernetes
+// itself. It is exposed as a go/analysis.Analyzer so it can be run via
+// `go vet -vettool`, dropped into golangci-lint, or driven directly by
+// hack/verify-imports-order.
+//
+// https://github.com/kubernetes/kubeadm/issues/2515
+package importorder
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var (
+	groupsFlag string
+	groupsFile string
+)
+
+// Analyzer reports import blocks that aren't grouped in the expected
+// order and suggests a fix that reorders them.
+var Analyzer = &analysis.Analyzer{
+	Name: "importorder",
+	Doc:  "check that imports are grouped in the conventional Kubernetes order",
+	Run:  run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&groupsFlag, "groups", "",
+		`comma-separated, ordered list of import groups (e.g. "stdlib,external,k8s.io,k8s.io/kubernetes"); overridden by -groups-config`)
+	Analyzer.Flags.StringVar(&groupsFile, "groups-config", "",
+		"path to a YAML or JSON file describing import groups; takes precedence over -groups")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	cfg, err := ResolveGroupConfig(groupsFlag, groupsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range pass.Files {
+		finding := Check(pass.Fset, cfg, file)
+		if finding == nil {
+			continue
+		}
+
+		message := fmt.Sprintf("imports are not grouped in the expected order (-got +want):\n%s", finding.Diff)
+		if finding.Constraint != "" {
+			message = fmt.Sprintf("[%s] %s", finding.Constraint, message)
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:     file.Imports[0].Pos(),
+			End:     file.Imports[len(file.Imports)-1].End(),
+			Message: message,
+		}
+		if finding.FixPos.IsValid() {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message: "reorder imports",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     finding.FixPos,
+					End:     finding.FixEnd,
+					NewText: []byte(finding.FixText),
+				}},
+			}}
+		}
+		pass.Report(diag)
+	}
+	return nil, nil
+}
+
+// ResolveGroupConfig resolves the import groups to check against:
+// groupsConfigPath, if set, takes precedence over groupsSpec, which takes
+// precedence over the default four-group Kubernetes layout. groupsSpec is
+// the comma-separated list accepted by the -groups flag.
+func ResolveGroupConfig(groupsSpec, groupsConfigPath string) (*GroupConfig, error) {
+	switch {
+	case groupsConfigPath != "":
+		return loadGroupConfigFile(groupsConfigPath)
+	case groupsSpec != "":
+		return parseGroupsFlag(groupsSpec)
+	default:
+		return defaultGroupConfig(), nil
+	}
+}